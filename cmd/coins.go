@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/DHDaniel/chrypto/coins"
+)
+
+var coinsCmd = &cobra.Command{
+	Use:   "coins SYMBOL...",
+	Short: "Fetch and store coin metadata and a market snapshot for one or more symbols",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := coins.FetchCoinList()
+		if err != nil {
+			return fmt.Errorf("fetching coin list: %w", err)
+		}
+		if err := coins.ValidateSymbols(list, args); err != nil {
+			return err
+		}
+		return syncMetadata(list, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(coinsCmd)
+}
+
+// syncMetadata populates the coins and market_snapshots tables for each symbol, which
+// must already be present (and validated) in list.
+func syncMetadata(list map[string]coins.Coin, symbols []string) error {
+	if err := createMetadataTablesIfNeeded(); err != nil {
+		return err
+	}
+	for _, symbol := range symbols {
+		coin := list[strings.ToUpper(symbol)]
+		if err := writeCoin(coin); err != nil {
+			return fmt.Errorf("writing coin metadata for %s: %w", symbol, err)
+		}
+	}
+	snapshots, err := coins.FetchMarketSnapshot(symbols)
+	if err != nil {
+		return fmt.Errorf("fetching market snapshot: %w", err)
+	}
+	for _, snapshot := range snapshots {
+		if err := writeMarketSnapshot(snapshot); err != nil {
+			return fmt.Errorf("writing market snapshot for %s: %w", snapshot.Symbol, err)
+		}
+	}
+	return nil
+}
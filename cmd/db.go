@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/shopspring/decimal"
+
+	"github.com/DHDaniel/chrypto/coins"
+	"github.com/DHDaniel/chrypto/provider"
+)
+
+var (
+	db *sql.DB // subcommands access this global variable to read and write to database
+)
+
+// Quote describes a specific moment in time for a cryptocurrency asset.
+// Prices are quoted in USD (USDT for the Binance provider). E.g Bitcoin's open, close, high, and low values would all be BTC -> USD.
+type Quote = provider.Quote
+
+func initializeDB(path string) (*sql.DB, error) {
+	// set database path and open a connection
+	database, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return database, err
+	}
+	if err := database.Ping(); err != nil {
+		return database, err
+	}
+	if err := migrateLegacyFloatTables(database); err != nil {
+		return database, err
+	}
+	return database, nil
+}
+
+// migrateLegacyFloatTables rewrites any quote table still using the old FLOAT columns
+// (from before prices were stored as decimal TEXT) to TEXT columns, preserving data by
+// round-tripping each value through decimal.NewFromFloat.
+func migrateLegacyFloatTables(database *sql.DB) error {
+	rows, err := database.Query(`SELECT name FROM sqlite_master WHERE type = "table"`)
+	if err != nil {
+		return err
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+
+	for _, table := range tables {
+		legacy, err := hasLegacyFloatColumn(database, table)
+		if err != nil {
+			return err
+		}
+		if legacy {
+			if err := migrateTableToDecimal(database, table); err != nil {
+				return fmt.Errorf("migrating table %q to decimal columns: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// hasLegacyFloatColumn reports whether table still has a FLOAT-typed close column.
+func hasLegacyFloatColumn(database *sql.DB, table string) (bool, error) {
+	rows, err := database.Query(fmt.Sprintf("PRAGMA table_info(\"%s\")", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cid int
+		var name, colType string
+		var notNull, pk int
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return false, err
+		}
+		if name == "close" && colType == "FLOAT" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// migrateTableToDecimal recreates table with TEXT OHLC columns, converting each existing
+// row's float values to their canonical decimal string representation.
+func migrateTableToDecimal(database *sql.DB, table string) error {
+	tx, err := database.Begin()
+	if err != nil {
+		return err
+	}
+	legacyName := table + "_legacy_float"
+	if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE \"%s\" RENAME TO \"%s\"", table, legacyName)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(newTableCommand(table)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	rows, err := tx.Query(fmt.Sprintf("SELECT time, close, high, low, open, volume_from, volume_to FROM \"%s\"", legacyName))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	insert := fmt.Sprintf("INSERT INTO \"%s\" (time, close, high, low, open, volume_from, volume_to) VALUES ($1, $2, $3, $4, $5, $6, $7)", table)
+	for rows.Next() {
+		var t int64
+		var close, high, low, open, volumeFrom, volumeTo float64
+		if err := rows.Scan(&t, &close, &high, &low, &open, &volumeFrom, &volumeTo); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.Exec(insert, t,
+			decimal.NewFromFloat(close).String(),
+			decimal.NewFromFloat(high).String(),
+			decimal.NewFromFloat(low).String(),
+			decimal.NewFromFloat(open).String(),
+			decimal.NewFromFloat(volumeFrom).String(),
+			decimal.NewFromFloat(volumeTo).String(),
+		); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return err
+		}
+	}
+	rows.Close()
+	if _, err := tx.Exec(fmt.Sprintf("DROP TABLE \"%s\"", legacyName)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// newTableCommand returns the CREATE TABLE statement used for a quote table.
+//
+// OHLC columns are plain TEXT, not zero-padded to a fixed width, so `ORDER BY close`
+// etc. sorts lexically rather than numerically (e.g. "10" sorts before "9"). Query
+// through decimal.NewFromString and sort in Go if numeric ordering is ever needed.
+func newTableCommand(symbol string) string {
+	return fmt.Sprintf("CREATE TABLE \"%s\" (time INT UNIQUE, close TEXT, high TEXT, low TEXT, open TEXT, volume_from TEXT, volume_to TEXT)", symbol)
+}
+
+func createTable(symbol string) (sql.Result, error) {
+	// create the table
+	result, err := db.Exec(newTableCommand(symbol))
+	if err != nil {
+		log.Printf("Could not create database table for: %s", symbol)
+		return result, err
+	}
+	// return nil error
+	return result, nil
+}
+
+// Creates a table if it does not exist, and returns the "created" boolean.
+func createTableIfNeeded(symbol string) (bool, error) {
+	exists, err := tableExists(symbol)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+	if _, err := createTable(symbol); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// tableExists reports whether a table with the given name has already been created.
+func tableExists(symbol string) (bool, error) {
+	var dummy string
+	query := fmt.Sprintf("SELECT name FROM sqlite_master WHERE type=\"table\" AND name=\"%s\"", symbol)
+	err := db.QueryRow(query).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// quoteTableBounds returns the earliest and latest stored quote times for symbol, so a
+// resumed fetch only needs to backfill the gaps before minTime and after maxTime rather
+// than redownloading everything. exists is false if the table hasn't been created yet
+// or holds no quotes.
+func quoteTableBounds(symbol string) (minTime, maxTime int64, exists bool, err error) {
+	exists, err = tableExists(symbol)
+	if err != nil || !exists {
+		return 0, 0, exists, err
+	}
+	query := fmt.Sprintf("SELECT MIN(time), MAX(time) FROM \"%s\"", symbol)
+	var min, max sql.NullInt64
+	if err := db.QueryRow(query).Scan(&min, &max); err != nil {
+		return 0, 0, true, err
+	}
+	if !min.Valid {
+		// table exists but is empty
+		return 0, 0, false, nil
+	}
+	return min.Int64, max.Int64, true, nil
+}
+
+func isDummyQuote(quote Quote) bool {
+	if quote.Open.IsZero() && quote.Close.IsZero() && quote.High.IsZero() && quote.Low.IsZero() {
+		return true
+	} else {
+		return false
+	}
+}
+
+// Resolves the path given to the command line flag and returns an absolute version.
+func resolvePath(dbpath string) (string, error) {
+	// determine if absolute or relative path
+	if path.IsAbs(dbpath) {
+		return dbpath, nil
+	} else {
+		// return resolved relative path
+		wd, err := os.Getwd()
+		dbpath = path.Join(wd, dbpath)
+		return dbpath, err
+	}
+}
+
+// Writes the given quotes to the database.
+func writeToDB(quotes []Quote, symbol string) (Quote, error) {
+	// if the table doesn't exist, we create it
+	_, err := createTableIfNeeded(symbol)
+	if err != nil {
+		log.Printf("Table creation for %s failed", symbol)
+		return Quote{}, err
+	}
+	// begin a transaction to lump together the quotes we are writing
+	tx, err := db.Begin()
+	if err != nil {
+		return Quote{}, err
+	}
+	// loop through quotes and add them to the database
+	for _, q := range quotes {
+		// check quote not empty. Might get an empty quote halfway through
+		if isDummyQuote(q) {
+			break
+		}
+		// create placeholder query using the symbol we used
+		query := fmt.Sprintf("INSERT INTO \"%s\" (time, close, high, low, open, volume_from, volume_to) VALUES ($1, $2, $3, $4, $5, $6, $7)", symbol)
+		// bind the canonical (non-scientific-notation) decimal string so the TEXT column holds
+		// exactly what the provider reported
+		_, err := tx.Exec(query, q.Time, q.Close.String(), q.High.String(), q.Low.String(), q.Open.String(), q.VolumeFrom.String(), q.VolumeTo.String())
+		// handle all cases of database errors
+		if err != nil {
+			driverErr, ok := err.(sqlite3.Error)
+			if !ok {
+				// if we couldn't convert for some reason, just return the error
+				return Quote{}, err
+			}
+			// run through cases
+			switch {
+			case driverErr.ExtendedCode == 2067:
+				// this indicates a UNIQUE constraint failed i.e writing duplicated data to DB
+				log.Printf("Duplicate value for %v timestamp %v. Skipping...", symbol, q.Time)
+				continue
+			default:
+				// generic error message
+				log.Printf("Write to %s failed", symbol)
+				return Quote{}, err
+			}
+		}
+	}
+	// commit transaction
+	tx.Commit()
+	earliest := quotes[0]
+	return earliest, nil
+}
+
+// closestClose returns the close price of the quote row for symbol whose time is
+// nearest to ts, e.g. to mark an open position to market as of "now".
+func closestClose(symbol string, ts int64) (decimal.Decimal, error) {
+	query := fmt.Sprintf("SELECT close FROM \"%s\" ORDER BY ABS(time - $1) ASC LIMIT 1", symbol)
+	var closeStr string
+	if err := db.QueryRow(query, ts).Scan(&closeStr); err != nil {
+		return decimal.Decimal{}, err
+	}
+	return decimal.NewFromString(closeStr)
+}
+
+// createMetadataTablesIfNeeded creates the coins and market_snapshots tables the first
+// time any metadata is written; both are simple enough to not need the per-symbol
+// treatment that quote tables get.
+func createMetadataTablesIfNeeded() error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS coins (
+		id TEXT PRIMARY KEY,
+		name TEXT,
+		symbol TEXT UNIQUE,
+		description TEXT,
+		total_coins_mined TEXT,
+		asset_launch_date TEXT,
+		built_on TEXT,
+		smart_contract_address TEXT
+	)`); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS market_snapshots (
+		symbol TEXT,
+		ts INT,
+		mktcap TEXT,
+		high_day TEXT,
+		low_day TEXT,
+		change_pct_hour TEXT,
+		change_pct_day TEXT,
+		change_pct_24h TEXT,
+		PRIMARY KEY (symbol, ts)
+	)`); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeCoin upserts a coin's descriptive metadata into the coins table.
+func writeCoin(coin coins.Coin) error {
+	_, err := db.Exec(`INSERT INTO coins (id, name, symbol, description, total_coins_mined, asset_launch_date, built_on, smart_contract_address)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT(symbol) DO UPDATE SET
+			id = excluded.id, name = excluded.name, description = excluded.description,
+			total_coins_mined = excluded.total_coins_mined, asset_launch_date = excluded.asset_launch_date,
+			built_on = excluded.built_on, smart_contract_address = excluded.smart_contract_address`,
+		coin.Id, coin.Name, coin.Symbol, coin.Description, coin.TotalCoinsMined, coin.AssetLaunchDate, coin.BuiltOn, coin.SmartContractAddress)
+	return err
+}
+
+// writeMarketSnapshot inserts a single point-in-time market snapshot row.
+func writeMarketSnapshot(snapshot coins.Snapshot) error {
+	_, err := db.Exec(`INSERT INTO market_snapshots (symbol, ts, mktcap, high_day, low_day, change_pct_hour, change_pct_day, change_pct_24h)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT(symbol, ts) DO NOTHING`,
+		snapshot.Symbol, snapshot.Ts,
+		snapshot.MktCap.String(), snapshot.HighDay.String(), snapshot.LowDay.String(),
+		snapshot.ChangePctHour.String(), snapshot.ChangePctDay.String(), snapshot.ChangePct24h.String())
+	return err
+}
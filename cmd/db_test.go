@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// TestDecimalRoundTrip asserts that prices parsed from strings and written back out via
+// String() come back as the same numeric value, including values a float64 can't
+// represent exactly. decimal.String() trims trailing zeros (e.g. "1.50" -> "1.5"), so
+// this compares numeric equality, not the literal string, after round-tripping through
+// String()/NewFromString the way writeToDB and a later read would.
+func TestDecimalRoundTrip(t *testing.T) {
+	values := []string{
+		"0.00000001234567890",
+		"18446744073709551616.5",
+		"0",
+		"1",
+	}
+	for _, v := range values {
+		want, err := decimal.NewFromString(v)
+		if err != nil {
+			t.Fatalf("NewFromString(%q) returned error: %v", v, err)
+		}
+		got, err := decimal.NewFromString(want.String())
+		if err != nil {
+			t.Fatalf("NewFromString(%q) returned error: %v", want.String(), err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("round trip for %q produced %q (%s != %s)", v, want.String(), got, want)
+		}
+	}
+}
+
+func TestIsDummyQuote(t *testing.T) {
+	zero := decimal.NewFromInt(0)
+	nonZero := decimal.NewFromFloat(0.00000001234567890)
+
+	dummy := Quote{Open: zero, Close: zero, High: zero, Low: zero}
+	if !isDummyQuote(dummy) {
+		t.Error("expected all-zero quote to be a dummy quote")
+	}
+
+	real := Quote{Open: nonZero, Close: nonZero, High: nonZero, Low: nonZero}
+	if isDummyQuote(real) {
+		t.Error("expected non-zero quote to not be a dummy quote")
+	}
+}
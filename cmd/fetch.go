@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/time/rate"
+
+	"github.com/DHDaniel/chrypto/coins"
+	"github.com/DHDaniel/chrypto/provider"
+)
+
+// historyLimit is the maximum number of historical quotes fetched per request.
+const historyLimit = 2000
+
+var (
+	providerName string  // market-data provider to fetch from, set by the --provider flag
+	withMetadata bool    // whether to also populate the coins/market_snapshots tables, set by --with-metadata
+	rps          float64 // requests/sec budget shared across all symbols being fetched, set by --rps
+)
+
+var fetchCmd = &cobra.Command{
+	Use:   "fetch SYMBOL...",
+	Short: "Fetch and store historical hourly quotes for one or more symbols",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prov, err := provider.Get(providerName)
+		if err != nil {
+			return err
+		}
+		symbols := args
+		// validate against the coin list first, so a typo like "BTK" fails loudly instead
+		// of silently creating an empty table
+		list, err := coins.FetchCoinList()
+		if err != nil {
+			return fmt.Errorf("fetching coin list: %w", err)
+		}
+		if err := coins.ValidateSymbols(list, symbols); err != nil {
+			return err
+		}
+		if withMetadata {
+			if err := syncMetadata(list, symbols); err != nil {
+				return fmt.Errorf("syncing coin metadata: %w", err)
+			}
+		}
+
+		// Ctrl-C cancels this context; in-flight fetches notice it between
+		// batches and stop there, rather than leaving a partial write, since each batch
+		// is already committed to the database as its own transaction.
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		// shared across every symbol's goroutine, so the whole fetch respects one
+		// provider-wide requests/sec budget instead of each goroutine sleeping independently
+		limiter := rate.NewLimiter(rate.Limit(rps), 1)
+
+		// create channels to receive on
+		donec, errc := make(chan string), make(chan error)
+		// go get each symbol's data concurrently
+		for _, symbol := range symbols {
+			log.Printf("Fetching historical data for: %v (via %s)", symbol, prov.Name())
+			go fetchSymbol(ctx, prov, limiter, symbol, donec, errc)
+		}
+		// this will block while it waits for channels to become available and send data
+		for i := 0; i < len(symbols); i++ {
+			select {
+			case done := <-donec:
+				log.Printf("Got all data for: %s", done)
+			case err := <-errc:
+				log.Println(err)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	fetchCmd.Flags().StringVar(&providerName, "provider", "cryptocompare", fmt.Sprintf("market-data provider to fetch from (one of: %v)", provider.Names()))
+	fetchCmd.Flags().BoolVar(&withMetadata, "with-metadata", false, "also populate the coins and market_snapshots tables for the fetched symbols")
+	fetchCmd.Flags().Float64Var(&rps, "rps", 4, "maximum requests per second across all symbols being fetched")
+	rootCmd.AddCommand(fetchCmd)
+}
+
+// fetchSymbol resumes (or starts) the backfill for a single symbol, reporting the
+// symbol on donec when it finishes or the error on errc when it fails.
+func fetchSymbol(ctx context.Context, prov provider.HistoricalProvider, limiter *rate.Limiter, symbol string, donec chan string, errc chan error) {
+	if err := backfillSymbol(ctx, prov, limiter, symbol); err != nil {
+		errc <- err
+		return
+	}
+	donec <- symbol
+}
+
+// backfillSymbol resumes fetching for symbol: it fills the gap after the newest stored
+// quote (if any) and the gap before the oldest stored quote, instead of redownloading
+// everything from scratch on every run.
+func backfillSymbol(ctx context.Context, prov provider.HistoricalProvider, limiter *rate.Limiter, symbol string) error {
+	minTime, maxTime, exists, err := quoteTableBounds(symbol)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fetchRange(ctx, prov, limiter, symbol, time.Now().Unix(), math.MinInt64)
+	}
+	if now := time.Now().Unix(); now > maxTime {
+		if err := fetchRange(ctx, prov, limiter, symbol, now, maxTime); err != nil {
+			return err
+		}
+	}
+	return fetchRange(ctx, prov, limiter, symbol, minTime-1, math.MinInt64)
+}
+
+// fetchRange pages backwards from unixtime, historyLimit quotes at a time, writing each
+// batch to the database, until it reaches stopAt (inclusive) or the provider runs out of
+// data (signalled by a dummy, all-zero quote or an empty response). Pass stopAt = math.MinInt64 to fetch all
+// the way back to the beginning of history.
+func fetchRange(ctx context.Context, prov provider.HistoricalProvider, limiter *rate.Limiter, symbol string, unixtime int64, stopAt int64) error {
+	for {
+		if ctx.Err() != nil {
+			// already-written batches are committed individually, so it's safe to just stop
+			return nil
+		}
+		if err := limiter.Wait(ctx); err != nil {
+			return nil
+		}
+		data, err := prov.FetchHourly(ctx, symbol, unixtime, historyLimit)
+		if err != nil {
+			return err
+		}
+		// exit if the provider has nothing more for us. CryptoCompare signals this with a
+		// dummy, all-zero quote; Binance just returns an empty/short slice once paging runs
+		// past the start of a pair's trading history, so an empty response means the same thing.
+		if len(data) == 0 {
+			return nil
+		}
+		if isDummyQuote(data[len(data)-1]) {
+			return nil
+		}
+		earliest, err := writeToDB(data, symbol)
+		if err != nil {
+			return err
+		}
+		if earliest.Time <= stopAt {
+			return nil
+		}
+		unixtime = earliest.Time - 1
+	}
+}
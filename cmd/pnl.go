@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pnlSymbol string // symbol being traded, set by the --symbol flag
+	pnlSince  string // only consider trades on or after this date, set by the --since flag
+)
+
+// trade is a single fill read from the trades CSV: time, side ("buy"/"sell"), qty, price.
+type trade struct {
+	Time  int64
+	Side  string
+	Qty   decimal.Decimal
+	Price decimal.Decimal
+}
+
+// lot is an unmatched buy, waiting to be closed out by a later sell (or marked to market).
+type lot struct {
+	Qty   decimal.Decimal
+	Price decimal.Decimal
+}
+
+var pnlCmd = &cobra.Command{
+	Use:   "pnl TRADES.csv",
+	Short: "Compute realized and unrealized P&L for a symbol from a CSV of trades",
+	Long: "pnl walks a CSV of trades (time, side, qty, price) against the stored quotes table, " +
+		"matching buys to sells FIFO to report realized P&L, and marks any remaining open " +
+		"position to market against the closest hourly close to report unrealized P&L.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if pnlSymbol == "" {
+			return fmt.Errorf("--symbol is required")
+		}
+		trades, err := readTrades(args[0])
+		if err != nil {
+			return fmt.Errorf("reading trades: %w", err)
+		}
+		if pnlSince != "" {
+			since, err := time.Parse("2006-01-02", pnlSince)
+			if err != nil {
+				return fmt.Errorf("parsing --since: %w", err)
+			}
+			trades = filterSince(trades, since.Unix())
+		}
+		realized, openLots := matchFIFO(trades)
+
+		now := time.Now().Unix()
+		latestClose, err := closestClose(pnlSymbol, now)
+		if err != nil {
+			return fmt.Errorf("looking up latest close for %s: %w", pnlSymbol, err)
+		}
+		unrealized := decimal.Zero
+		openQty := decimal.Zero
+		for _, l := range openLots {
+			unrealized = unrealized.Add(latestClose.Sub(l.Price).Mul(l.Qty))
+			openQty = openQty.Add(l.Qty)
+		}
+
+		fmt.Printf("Realized P&L:    %s\n", realized.StringFixed(2))
+		fmt.Printf("Open position:   %s %s\n", openQty.String(), pnlSymbol)
+		fmt.Printf("Latest close:    %s\n", latestClose.String())
+		fmt.Printf("Unrealized P&L:  %s\n", unrealized.StringFixed(2))
+		return nil
+	},
+}
+
+func init() {
+	pnlCmd.Flags().StringVar(&pnlSymbol, "symbol", "", "symbol to compute P&L for, e.g. BTC")
+	pnlCmd.Flags().StringVar(&pnlSince, "since", "", "only consider trades on or after this date (YYYY-MM-DD)")
+	rootCmd.AddCommand(pnlCmd)
+}
+
+// readTrades parses a CSV of (time, side, qty, price) rows. A header row is tolerated:
+// any row whose time field fails to parse as an integer is skipped.
+func readTrades(path string) ([]trade, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = 4
+	var trades []trade
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		ts, err := strconv.ParseInt(strings.TrimSpace(record[0]), 10, 64)
+		if err != nil {
+			// likely a header row; skip it
+			continue
+		}
+		qty, err := decimal.NewFromString(strings.TrimSpace(record[2]))
+		if err != nil {
+			return nil, fmt.Errorf("parsing qty %q: %w", record[2], err)
+		}
+		price, err := decimal.NewFromString(strings.TrimSpace(record[3]))
+		if err != nil {
+			return nil, fmt.Errorf("parsing price %q: %w", record[3], err)
+		}
+		trades = append(trades, trade{
+			Time:  ts,
+			Side:  strings.ToLower(strings.TrimSpace(record[1])),
+			Qty:   qty,
+			Price: price,
+		})
+	}
+	sort.Slice(trades, func(i, j int) bool { return trades[i].Time < trades[j].Time })
+	return trades, nil
+}
+
+// filterSince returns only the trades at or after sinceTs.
+func filterSince(trades []trade, sinceTs int64) []trade {
+	filtered := make([]trade, 0, len(trades))
+	for _, t := range trades {
+		if t.Time >= sinceTs {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// matchFIFO walks trades in time order, matching each sell against the oldest open buy
+// lots first, and returns the total realized P&L plus whatever lots remain open.
+func matchFIFO(trades []trade) (decimal.Decimal, []lot) {
+	var openLots []lot
+	realized := decimal.Zero
+	for _, t := range trades {
+		switch t.Side {
+		case "buy":
+			openLots = append(openLots, lot{Qty: t.Qty, Price: t.Price})
+		case "sell":
+			remaining := t.Qty
+			for remaining.IsPositive() && len(openLots) > 0 {
+				l := &openLots[0]
+				matched := decimal.Min(remaining, l.Qty)
+				realized = realized.Add(t.Price.Sub(l.Price).Mul(matched))
+				l.Qty = l.Qty.Sub(matched)
+				remaining = remaining.Sub(matched)
+				if l.Qty.IsZero() {
+					openLots = openLots[1:]
+				}
+			}
+		}
+	}
+	return realized, openLots
+}
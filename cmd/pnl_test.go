@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func mustDecimal(t *testing.T, s string) decimal.Decimal {
+	t.Helper()
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", s, err)
+	}
+	return d
+}
+
+func TestMatchFIFOFullyClosedPosition(t *testing.T) {
+	trades := []trade{
+		{Time: 1, Side: "buy", Qty: mustDecimal(t, "1"), Price: mustDecimal(t, "100")},
+		{Time: 2, Side: "buy", Qty: mustDecimal(t, "1"), Price: mustDecimal(t, "200")},
+		{Time: 3, Side: "sell", Qty: mustDecimal(t, "2"), Price: mustDecimal(t, "300")},
+	}
+	realized, openLots := matchFIFO(trades)
+	// FIFO: first lot (100) and second lot (200) both sold at 300 -> (200) + (100) = 300
+	if want := mustDecimal(t, "300"); !realized.Equal(want) {
+		t.Errorf("realized = %s, want %s", realized, want)
+	}
+	if len(openLots) != 0 {
+		t.Errorf("expected no open lots, got %v", openLots)
+	}
+}
+
+func TestMatchFIFOPartialSell(t *testing.T) {
+	trades := []trade{
+		{Time: 1, Side: "buy", Qty: mustDecimal(t, "2"), Price: mustDecimal(t, "100")},
+		{Time: 2, Side: "sell", Qty: mustDecimal(t, "1"), Price: mustDecimal(t, "150")},
+	}
+	realized, openLots := matchFIFO(trades)
+	if want := mustDecimal(t, "50"); !realized.Equal(want) {
+		t.Errorf("realized = %s, want %s", realized, want)
+	}
+	if len(openLots) != 1 || !openLots[0].Qty.Equal(mustDecimal(t, "1")) {
+		t.Errorf("expected one open lot of qty 1, got %v", openLots)
+	}
+}
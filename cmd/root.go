@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	dbpath string // path to the sqlite database, set by the --dbpath persistent flag
+)
+
+// rootCmd is the base command; it holds no behavior of its own beyond dialing the database
+// open for every subcommand.
+var rootCmd = &cobra.Command{
+	Use:   "chrypto",
+	Short: "chrypto fetches and analyzes historical cryptocurrency price data",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		resPath, err := resolvePath(dbpath)
+		if err != nil {
+			return err
+		}
+		db, err = initializeDB(resPath)
+		return err
+	},
+}
+
+// Execute runs the root command, exiting the process on failure.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&dbpath, "dbpath", "./historical.db", "path to the database file where information will be stored.")
+}
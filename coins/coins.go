@@ -0,0 +1,140 @@
+// Package coins fetches descriptive coin metadata and point-in-time market
+// snapshots from CryptoCompare, to give the raw hourly OHLC tables some
+// descriptive context (contract address, launch date, total supply, mkt cap).
+package coins
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Coin is the descriptive metadata CryptoCompare keeps about a listed asset.
+type Coin struct {
+	Id                   string `json:"Id"`
+	Name                 string `json:"CoinName"`
+	Symbol               string `json:"Symbol"`
+	Description          string `json:"Description"`
+	TotalCoinsMined      string `json:"TotalCoinsMined"`
+	AssetLaunchDate      string `json:"AssetLaunchDate"`
+	BuiltOn              string `json:"BuiltOn"`
+	SmartContractAddress string `json:"SmartContractAddress"`
+}
+
+// coinListResponse is the envelope returned by /data/all/coinlist.
+type coinListResponse struct {
+	Response string          `json:"Response"`
+	Message  string          `json:"Message"`
+	Data     map[string]Coin `json:"Data"`
+}
+
+// FetchCoinList downloads the full CryptoCompare coin list, keyed by symbol.
+func FetchCoinList() (map[string]Coin, error) {
+	res, err := http.Get("https://min-api.cryptocompare.com/data/all/coinlist")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	var parsed coinListResponse
+	if err := json.NewDecoder(res.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("parsing coinlist response: %w", err)
+	}
+	if parsed.Response != "" && parsed.Response != "Success" {
+		return nil, fmt.Errorf("coinlist request failed: %s", parsed.Message)
+	}
+	return parsed.Data, nil
+}
+
+// ValidateSymbols checks every symbol against the coin list so that typos like "BTK"
+// fail loudly instead of silently creating an empty table.
+func ValidateSymbols(list map[string]Coin, symbols []string) error {
+	var unknown []string
+	for _, symbol := range symbols {
+		if _, ok := list[strings.ToUpper(symbol)]; !ok {
+			unknown = append(unknown, symbol)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown symbol(s) not found in CryptoCompare coin list: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Snapshot is a single symbol's market data as of a point in time: market cap, the
+// day's high/low, and percentage changes over a few windows.
+type Snapshot struct {
+	Symbol        string
+	Ts            int64
+	MktCap        decimal.Decimal
+	HighDay       decimal.Decimal
+	LowDay        decimal.Decimal
+	ChangePctHour decimal.Decimal
+	ChangePctDay  decimal.Decimal
+	ChangePct24h  decimal.Decimal
+}
+
+// rawSnapshot mirrors the USD object nested under each symbol in /data/pricemultifull's
+// RAW section; CryptoCompare's field names are abbreviations of the ones we store.
+//
+// The price/mktcap fields are decimal.Decimal, not float64: these are per-symbol USD
+// values subject to the same low-priced-asset precision loss as Quote (see its comment).
+type rawSnapshot struct {
+	MKTCAP          decimal.Decimal `json:"MKTCAP"`
+	HIGHDAY         decimal.Decimal `json:"HIGHDAY"`
+	LOWDAY          decimal.Decimal `json:"LOWDAY"`
+	CHANGEPCTHOUR   decimal.Decimal `json:"CHANGEPCTHOUR"`
+	CHANGEPCTDAY    decimal.Decimal `json:"CHANGEPCTDAY"`
+	CHANGEPCT24HOUR decimal.Decimal `json:"CHANGEPCT24HOUR"`
+	LASTUPDATE      int64           `json:"LASTUPDATE"`
+}
+
+// priceMultiFullResponse is the envelope returned by /data/pricemultifull.
+type priceMultiFullResponse struct {
+	Raw map[string]map[string]rawSnapshot `json:"RAW"`
+}
+
+// FetchMarketSnapshot downloads a point-in-time USD market snapshot for each symbol.
+// Symbols are uppercased before querying: CryptoCompare's RAW map is keyed by its
+// canonical (uppercase) symbol, same as the coin list, regardless of the case callers pass in.
+func FetchMarketSnapshot(symbols []string) ([]Snapshot, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+	canonical := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		canonical[i] = strings.ToUpper(symbol)
+	}
+	query := fmt.Sprintf("https://min-api.cryptocompare.com/data/pricemultifull?fsyms=%s&tsyms=USD", strings.Join(canonical, ","))
+	res, err := http.Get(query)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	var parsed priceMultiFullResponse
+	if err := json.NewDecoder(res.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("parsing pricemultifull response: %w", err)
+	}
+	snapshots := make([]Snapshot, 0, len(canonical))
+	for _, symbol := range canonical {
+		raw, ok := parsed.Raw[symbol]["USD"]
+		if !ok {
+			return nil, fmt.Errorf("no USD snapshot returned for %s", symbol)
+		}
+		snapshots = append(snapshots, Snapshot{
+			// stored in the same canonical (uppercase) casing as Coin.Symbol, so the
+			// coins and market_snapshots tables can be joined on symbol
+			Symbol:        symbol,
+			Ts:            raw.LASTUPDATE,
+			MktCap:        raw.MKTCAP,
+			HighDay:       raw.HIGHDAY,
+			LowDay:        raw.LOWDAY,
+			ChangePctHour: raw.CHANGEPCTHOUR,
+			ChangePctDay:  raw.CHANGEPCTDAY,
+			ChangePct24h:  raw.CHANGEPCT24HOUR,
+		})
+	}
+	return snapshots, nil
+}
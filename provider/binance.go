@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// BinanceProvider fetches historical quotes from Binance's public klines endpoint.
+// Symbols are expected in CryptoCompare style (e.g. "BTC") and are quoted against
+// USDT, since Binance has no direct USD market for most assets.
+type BinanceProvider struct{}
+
+// NewBinanceProvider returns a HistoricalProvider backed by Binance.
+func NewBinanceProvider() *BinanceProvider {
+	return &BinanceProvider{}
+}
+
+func (p *BinanceProvider) Name() string {
+	return "binance"
+}
+
+func (p *BinanceProvider) FetchHourly(ctx context.Context, symbol string, toTs int64, limit int) ([]Quote, error) {
+	return p.fetch(ctx, "1h", symbol, toTs, limit)
+}
+
+func (p *BinanceProvider) FetchDaily(ctx context.Context, symbol string, toTs int64, limit int) ([]Quote, error) {
+	return p.fetch(ctx, "1d", symbol, toTs, limit)
+}
+
+// kline is a single row of Binance's klines response, documented at
+// https://binance-docs.github.io/apidocs/spot/en/#kline-candlestick-data as a
+// heterogeneous JSON array rather than an object.
+type kline []interface{}
+
+func (p *BinanceProvider) fetch(ctx context.Context, interval, symbol string, toTs int64, limit int) ([]Quote, error) {
+	// Binance's symbol parameter is case-sensitive and expects uppercase, e.g. "BTCUSDT"
+	pair := strings.ToUpper(symbol) + "USDT"
+	query := fmt.Sprintf("https://api.binance.com/api/v3/klines?symbol=%s&interval=%s&endTime=%d&limit=%d", pair, interval, toTs*1000, limit)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	var rows []kline
+	if err := json.NewDecoder(res.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("parsing binance response for %s: %w", pair, err)
+	}
+	quotes := make([]Quote, 0, len(rows))
+	for _, row := range rows {
+		q, err := quoteFromKline(row)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, q)
+	}
+	return quotes, nil
+}
+
+// quoteFromKline converts a single Binance kline row into a Quote. The row layout is:
+// [openTime, open, high, low, close, volume, closeTime, quoteAssetVolume, ...].
+func quoteFromKline(row kline) (Quote, error) {
+	if len(row) < 8 {
+		return Quote{}, fmt.Errorf("unexpected kline row length: %d", len(row))
+	}
+	openTimeMs, ok := row[0].(float64)
+	if !ok {
+		return Quote{}, fmt.Errorf("unexpected kline open time type: %T", row[0])
+	}
+	open, err := parseKlineDecimal(row[1])
+	if err != nil {
+		return Quote{}, err
+	}
+	high, err := parseKlineDecimal(row[2])
+	if err != nil {
+		return Quote{}, err
+	}
+	low, err := parseKlineDecimal(row[3])
+	if err != nil {
+		return Quote{}, err
+	}
+	close, err := parseKlineDecimal(row[4])
+	if err != nil {
+		return Quote{}, err
+	}
+	volumeFrom, err := parseKlineDecimal(row[5])
+	if err != nil {
+		return Quote{}, err
+	}
+	volumeTo, err := parseKlineDecimal(row[7])
+	if err != nil {
+		return Quote{}, err
+	}
+	return Quote{
+		Time:       int64(openTimeMs) / 1000,
+		Open:       open,
+		High:       high,
+		Low:        low,
+		Close:      close,
+		VolumeFrom: volumeFrom,
+		VolumeTo:   volumeTo,
+	}, nil
+}
+
+// parseKlineDecimal converts a kline field to a decimal.Decimal. Binance returns OHLCV
+// fields as JSON strings (to preserve precision), unlike the timestamps.
+func parseKlineDecimal(v interface{}) (decimal.Decimal, error) {
+	s, ok := v.(string)
+	if !ok {
+		return decimal.Decimal{}, fmt.Errorf("unexpected kline field type: %T", v)
+	}
+	return decimal.NewFromString(s)
+}
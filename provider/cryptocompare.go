@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// cryptoCompareResponse is the envelope returned by the CryptoCompare histo* endpoints.
+type cryptoCompareResponse struct {
+	Response          string  `json:"Response"`
+	Type              int     `json:"Type"`
+	Aggregated        bool    `json:"Aggregated"`
+	Data              []Quote `json:"Data"` // this is what we're really interested in
+	TimeTo            int     `json:"TimeTo"`
+	TimeFrom          int     `json:"TimeFrom"`
+	FirstValueInArray bool    `json:"FirstValueInArray"`
+	ConversionType    struct {
+		Type             string `json:"type"`
+		ConversionSymbol string `json:"conversionSymbol"`
+	} `json:"ConversionType"`
+}
+
+// maxRetries is how many times a request is retried after a 429/5xx before giving up.
+const maxRetries = 5
+
+// CryptoCompareProvider fetches historical quotes from the CryptoCompare API.
+type CryptoCompareProvider struct{}
+
+// NewCryptoCompareProvider returns a HistoricalProvider backed by CryptoCompare.
+func NewCryptoCompareProvider() *CryptoCompareProvider {
+	return &CryptoCompareProvider{}
+}
+
+func (p *CryptoCompareProvider) Name() string {
+	return "cryptocompare"
+}
+
+func (p *CryptoCompareProvider) FetchHourly(ctx context.Context, symbol string, toTs int64, limit int) ([]Quote, error) {
+	return p.fetch(ctx, "histohour", symbol, toTs, limit)
+}
+
+func (p *CryptoCompareProvider) FetchDaily(ctx context.Context, symbol string, toTs int64, limit int) ([]Quote, error) {
+	return p.fetch(ctx, "histoday", symbol, toTs, limit)
+}
+
+func (p *CryptoCompareProvider) fetch(ctx context.Context, endpoint, symbol string, toTs int64, limit int) ([]Quote, error) {
+	query := fmt.Sprintf("https://min-api.cryptocompare.com/data/%s?fsym=%s&tsym=USD&limit=%d&aggregate=1&toTs=%v", endpoint, symbol, limit, toTs)
+
+	var info cryptoCompareResponse
+	backoff := 500 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500 {
+			res.Body.Close()
+			if attempt >= maxRetries {
+				return nil, fmt.Errorf("cryptocompare: giving up on %s after %d retries (last status %d)", symbol, attempt, res.StatusCode)
+			}
+			log.Printf("cryptocompare: got status %d for %s, retrying in %s", res.StatusCode, symbol, backoff)
+			if err := sleepOrCancel(ctx, backoff); err != nil {
+				return nil, err
+			}
+			backoff *= 2
+			continue
+		}
+		// parse JSON response and catch any errors
+		err = json.NewDecoder(res.Body).Decode(&info)
+		res.Body.Close()
+		if err != nil {
+			log.Print("There was an error parsing the response:", err)
+			return nil, err
+		}
+		break
+	}
+	// return only the slice of Quotes
+	return info.Data, nil
+}
+
+// sleepOrCancel waits for d, returning early with ctx's error if ctx is cancelled first.
+func sleepOrCancel(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
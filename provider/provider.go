@@ -0,0 +1,66 @@
+// Package provider defines the interface that market-data backends implement,
+// so the rest of chrypto can fetch historical quotes without caring whether
+// they came from CryptoCompare, Binance, or something else entirely.
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Quote describes a specific moment in time for a cryptocurrency asset.
+// Prices are quoted in USD (USDT for the Binance provider). E.g Bitcoin's open, close, high, and low values would all be BTC -> USD.
+//
+// Prices are decimal.Decimal rather than float64: exchange-reported values for
+// low-priced assets (e.g. SHIB, XRP satoshi-level moves) routinely exceed what
+// a float64 significand can represent exactly, which silently corrupts stored data.
+type Quote struct {
+	Time       int64           `json:"time"` // is a unix timestamp
+	Close      decimal.Decimal `json:"close"`
+	High       decimal.Decimal `json:"high"`
+	Low        decimal.Decimal `json:"low"`
+	Open       decimal.Decimal `json:"open"`
+	VolumeFrom decimal.Decimal `json:"volumefrom"`
+	VolumeTo   decimal.Decimal `json:"volumeto"`
+}
+
+// HistoricalProvider is implemented by anything that can serve historical OHLC
+// quotes for a symbol. toTs is a unix timestamp; results are returned in
+// ascending order (quotes[0] is the oldest quote in the batch), same as the
+// CryptoCompare API, so callers can page backwards through history by
+// re-calling with the earliest time seen.
+type HistoricalProvider interface {
+	// FetchHourly gets up to limit hourly quotes for symbol, ending at toTs. ctx governs
+	// cancellation of the underlying HTTP request, e.g. on Ctrl-C.
+	FetchHourly(ctx context.Context, symbol string, toTs int64, limit int) ([]Quote, error)
+	// FetchDaily gets up to limit daily quotes for symbol, ending at toTs.
+	FetchDaily(ctx context.Context, symbol string, toTs int64, limit int) ([]Quote, error)
+	// Name identifies the provider, e.g. for log messages.
+	Name() string
+}
+
+// registry of providers known to chrypto, keyed by the name used with --provider.
+var registry = map[string]func() HistoricalProvider{
+	"cryptocompare": func() HistoricalProvider { return NewCryptoCompareProvider() },
+	"binance":       func() HistoricalProvider { return NewBinanceProvider() },
+}
+
+// Get looks up a provider by name, as passed to the --provider flag.
+func Get(name string) (HistoricalProvider, error) {
+	factory, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider: %q (known providers: %v)", name, Names())
+	}
+	return factory(), nil
+}
+
+// Names returns the names of all known providers, for use in help text and errors.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	return names
+}